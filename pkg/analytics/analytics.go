@@ -0,0 +1,158 @@
+/*
+Copyright © 2022 The OpenCli Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package analytics ships opt-in, anonymous usage events for kbcli's
+// dbcluster lifecycle commands; describe is the only command that sends
+// events at present. It is disabled by
+// default; see Enabled, SetEnabled and EnsurePrompted for the opt-in gate,
+// and the analytics {enable,disable,status} subcommand for the user-facing
+// toggle.
+package analytics
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// EnvEnable opts a user in to telemetry for the current invocation, even
+// without a saved ~/.kbcli/config toggle.
+const EnvEnable = "KBCLI_ENABLE_ANALYTICS"
+
+const (
+	defaultEndpoint = "https://analytics.infracreate.com/v1/events"
+	postTimeout     = 2 * time.Second
+)
+
+var namespacesGVR = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+
+// sendWG tracks in-flight Send goroutines so a short-lived command can give
+// them a bounded chance to finish before the process exits; see Wait.
+var sendWG sync.WaitGroup
+
+// Event is a single anonymous usage event. Every field is listed here
+// because every field is transmitted — there is nothing else in the
+// payload, and in particular no cluster name, namespace, or other
+// user-identifying value.
+type Event struct {
+	// EventType is the command that fired the event, e.g. "describe".
+	EventType string `json:"eventType"`
+	// Engine is the database engine the command targeted, e.g. "mysql".
+	Engine string `json:"engine,omitempty"`
+	// Topology is "Standalone" or "Cluster".
+	Topology string `json:"topology,omitempty"`
+	// InstanceCount is the number of instances in the targeted cluster.
+	InstanceCount int64 `json:"instanceCount,omitempty"`
+	// KbcliVersion is the running kbcli build version.
+	KbcliVersion string `json:"kbcliVersion"`
+	// K8sVersion is the target cluster's reported server version.
+	K8sVersion string `json:"k8sVersion,omitempty"`
+	// ClientID is a stable, anonymous identifier derived from the target
+	// cluster's kube-system namespace UID (see ClientID below). It cannot be
+	// reversed to that UID, let alone to a cluster or user identity.
+	ClientID string `json:"clientId"`
+}
+
+// Client posts Events to the analytics endpoint.
+type Client struct {
+	Endpoint   string
+	HTTPClient *http.Client
+}
+
+// NewClient returns a Client posting to the default analytics endpoint with
+// a postTimeout deadline.
+func NewClient() *Client {
+	return &Client{
+		Endpoint:   defaultEndpoint,
+		HTTPClient: &http.Client{Timeout: postTimeout},
+	}
+}
+
+// Send posts event in the background if telemetry is enabled. It never
+// blocks the caller and never surfaces an error — a dropped or slow
+// analytics event must never affect command behavior or exit time.
+func (c *Client) Send(event Event) {
+	if !Enabled() {
+		return
+	}
+	sendWG.Add(1)
+	go func() {
+		defer sendWG.Done()
+		body, err := json.Marshal(event)
+		if err != nil {
+			return
+		}
+		req, err := http.NewRequest(http.MethodPost, c.Endpoint, bytes.NewReader(body))
+		if err != nil {
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := c.HTTPClient.Do(req)
+		if err != nil {
+			return
+		}
+		_ = resp.Body.Close()
+	}()
+}
+
+// Wait blocks until every in-flight Send has completed, or until timeout
+// elapses, whichever comes first. A short-lived command like describe should
+// defer a call to this after the last Send, otherwise its process routinely
+// exits before the 2s-timeout POST ever completes.
+func Wait(timeout time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		sendWG.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+	}
+}
+
+// ClientID derives a stable anonymous identifier for the cluster client
+// talks to, from a one-way hash of its kube-system namespace UID. The UID
+// itself is never transmitted.
+func ClientID(ctx context.Context, client dynamic.Interface) (string, error) {
+	ns, err := client.Resource(namespacesGVR).Get(ctx, "kube-system", metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(ns.GetUID()))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Version returns the kbcli build version reported in telemetry events,
+// falling back to "unknown" when build info isn't embedded (e.g. a `go run`
+// build).
+func Version() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "unknown"
+}
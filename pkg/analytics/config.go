@@ -0,0 +1,129 @@
+/*
+Copyright © 2022 The OpenCli Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analytics
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// config is the on-disk toggle stored at ~/.kbcli/config.
+type config struct {
+	Enabled  bool `json:"enabled"`
+	Prompted bool `json:"prompted"`
+}
+
+func configPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".kbcli", "config"), nil
+}
+
+func loadConfig() (config, error) {
+	path, err := configPath()
+	if err != nil {
+		return config{}, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return config{}, nil
+	}
+	if err != nil {
+		return config{}, err
+	}
+	var cfg config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return config{}, err
+	}
+	return cfg, nil
+}
+
+func saveConfig(cfg config) error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// Enabled reports whether telemetry should be sent. EnvEnable always wins
+// over the on-disk toggle; a fresh install with neither set sends nothing.
+func Enabled() bool {
+	if v, ok := os.LookupEnv(EnvEnable); ok {
+		return v != "" && v != "0" && !strings.EqualFold(v, "false")
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return false
+	}
+	return cfg.Enabled
+}
+
+// SetEnabled persists the user's enable/disable choice to ~/.kbcli/config.
+func SetEnabled(enabled bool) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	cfg.Enabled = enabled
+	cfg.Prompted = true
+	return saveConfig(cfg)
+}
+
+// EnsurePrompted shows a one-time opt-in prompt the first time any
+// dbcluster lifecycle command runs, defaulting to disabled unless the user
+// answers "y". It is a no-op once the user has been prompted, or when
+// EnvEnable is already set.
+func EnsurePrompted(out io.Writer, in io.Reader) error {
+	if _, ok := os.LookupEnv(EnvEnable); ok {
+		return nil
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if cfg.Prompted {
+		return nil
+	}
+
+	fmt.Fprintln(out, "kbcli can send anonymous usage telemetry (command, engine, topology, "+
+		"instance count, kbcli/k8s versions, and a one-way hash of your cluster's kube-system "+
+		"UID) to help us prioritize development. Never cluster names, namespaces, or other "+
+		"identifying data.")
+	fmt.Fprint(out, "Enable anonymous usage telemetry? [y/N]: ")
+
+	line, _ := bufio.NewReader(in).ReadString('\n')
+	cfg.Enabled = strings.EqualFold(strings.TrimSpace(line), "y")
+	cfg.Prompted = true
+	return saveConfig(cfg)
+}
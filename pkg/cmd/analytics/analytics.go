@@ -0,0 +1,84 @@
+/*
+Copyright © 2022 The OpenCli Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analytics
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"jihulab.com/infracreate/dbaas-system/opencli/pkg/analytics"
+)
+
+// NewAnalyticsCmd lets operators audit and control kbcli's opt-in usage
+// telemetry, documented in pkg/analytics.
+func NewAnalyticsCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "analytics",
+		Short: "Manage kbcli's anonymous usage telemetry",
+	}
+
+	cmd.AddCommand(newEnableCmd(streams))
+	cmd.AddCommand(newDisableCmd(streams))
+	cmd.AddCommand(newStatusCmd(streams))
+
+	return cmd
+}
+
+func newEnableCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	return &cobra.Command{
+		Use:   "enable",
+		Short: "Enable anonymous usage telemetry",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := analytics.SetEnabled(true); err != nil {
+				return err
+			}
+			fmt.Fprintln(streams.Out, "Anonymous usage telemetry enabled.")
+			return nil
+		},
+	}
+}
+
+func newDisableCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	return &cobra.Command{
+		Use:   "disable",
+		Short: "Disable anonymous usage telemetry",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := analytics.SetEnabled(false); err != nil {
+				return err
+			}
+			fmt.Fprintln(streams.Out, "Anonymous usage telemetry disabled.")
+			return nil
+		},
+	}
+}
+
+func newStatusCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show whether anonymous usage telemetry is enabled",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if analytics.Enabled() {
+				fmt.Fprintln(streams.Out, "enabled")
+			} else {
+				fmt.Fprintln(streams.Out, "disabled")
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright © 2022 The OpenCli Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dbcluster
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/clientcmd"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// contextTarget is one kubeconfig context describe fans out to when
+// --contexts/--all-contexts is set. name is empty for the single, default
+// target used when neither flag is given.
+type contextTarget struct {
+	name             string
+	namespace        string
+	enforceNamespace bool
+	client           dynamic.Interface
+	newBuilder       func() *resource.Builder
+}
+
+func (t contextTarget) label() string {
+	if t.name == "" {
+		return "current context"
+	}
+	return fmt.Sprintf("context %q", t.name)
+}
+
+// buildContextTargets resolves --contexts/--all-contexts into one
+// contextTarget per selected kubeconfig context, each carrying its own
+// dynamic client and resource.Builder so Run can fan out across them.
+func (o *DescribeOptions) buildContextTargets(f cmdutil.Factory) ([]contextTarget, error) {
+	rawConfig, err := f.ToRawKubeConfigLoader().RawConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	contextNames := o.Contexts
+	if o.AllContexts {
+		contextNames = nil
+		for name := range rawConfig.Contexts {
+			contextNames = append(contextNames, name)
+		}
+		sort.Strings(contextNames)
+	}
+
+	targets := make([]contextTarget, 0, len(contextNames))
+	for _, name := range contextNames {
+		name := name
+		if _, ok := rawConfig.Contexts[name]; !ok {
+			return nil, fmt.Errorf("context %q not found in kubeconfig", name)
+		}
+
+		clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+			clientcmd.NewDefaultClientConfigLoadingRules(),
+			&clientcmd.ConfigOverrides{CurrentContext: name},
+		)
+
+		namespace, enforceNamespace, err := clientConfig.Namespace()
+		if err != nil {
+			return nil, fmt.Errorf("resolving namespace for context %q: %w", name, err)
+		}
+		if o.AllNamespaces {
+			enforceNamespace = false
+		}
+
+		restConfig, err := clientConfig.ClientConfig()
+		if err != nil {
+			return nil, fmt.Errorf("building client config for context %q: %w", name, err)
+		}
+
+		client, err := dynamic.NewForConfig(restConfig)
+		if err != nil {
+			return nil, fmt.Errorf("building client for context %q: %w", name, err)
+		}
+
+		configFlags := genericclioptions.NewConfigFlags(true)
+		configFlags.Context = &name
+		contextFactory := cmdutil.NewFactory(cmdutil.NewMatchVersionFlags(configFlags))
+
+		targets = append(targets, contextTarget{
+			name:             name,
+			namespace:        namespace,
+			enforceNamespace: enforceNamespace,
+			client:           client,
+			newBuilder:       contextFactory.NewBuilder,
+		})
+	}
+
+	return targets, nil
+}
@@ -19,25 +19,42 @@ package dbcluster
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/cli-runtime/pkg/resource"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/describe"
 
+	"jihulab.com/infracreate/dbaas-system/opencli/pkg/analytics"
 	"jihulab.com/infracreate/dbaas-system/opencli/pkg/cmd/playground"
 	"jihulab.com/infracreate/dbaas-system/opencli/pkg/types"
 	"jihulab.com/infracreate/dbaas-system/opencli/pkg/utils"
 )
 
+// terminalClusterStatuses are the status.cluster.status values past which a
+// --watch session should stop polling and exit, the cluster having reached a
+// steady state.
+var terminalClusterStatuses = sets.NewString("Running", "Failed")
+
+// maxContextConcurrency bounds how many --contexts/--all-contexts targets
+// Run describes at once.
+const maxContextConcurrency = 8
+
 type DescribeOptions struct {
 	Namespace string
 
@@ -49,10 +66,48 @@ type DescribeOptions struct {
 	EnforceNamespace bool
 	AllNamespaces    bool
 
+	// Contexts and AllContexts fan describe out across multiple kubeconfig
+	// contexts instead of just the current one; see buildContextTargets.
+	Contexts    []string
+	AllContexts bool
+	targets     []contextTarget
+	// printMu serializes writes to IOStreams.Out/ErrOut across the
+	// concurrent per-context goroutines Run spawns.
+	printMu sync.Mutex
+
+	// Watch keeps describe running, re-rendering DBClusterInfo every time the
+	// underlying object changes, until the cluster reaches a terminal status
+	// or Timeout elapses.
+	Watch bool
+	// WatchInterval is how often describe also does a plain re-fetch
+	// alongside the watch events, guarding against a watch that silently
+	// stalls. The re-fetch fires on this interval whether or not events
+	// arrive in between.
+	WatchInterval time.Duration
+	// Timeout bounds how long a --watch session may run; zero means wait
+	// forever.
+	Timeout time.Duration
+
 	DescriberSettings *describe.DescriberSettings
 	FilenameOptions   *resource.FilenameOptions
 
+	// PrintFlags drives -o json|yaml|name|jsonpath=...|go-template=.... Note
+	// custom-columns isn't supported: genericclioptions.PrintFlags doesn't
+	// wire a custom-columns printer (that lives in kubectl's
+	// cmd/get.PrintFlags), and dbClusterInfoList's items aren't
+	// runtime.Objects, so the generic custom-columns printer couldn't
+	// iterate them even if it were plugged in.
+	// "" and "wide" are handled directly by utils.PrintClusterInfo instead.
+	PrintFlags *genericclioptions.PrintFlags
+
 	client dynamic.Interface
+
+	analyticsClient *analytics.Client
+	// clientID and k8sVersion are resolved once in Complete and attached to
+	// every analytics event this invocation sends.
+	clientID   string
+	k8sVersion string
+
 	genericclioptions.IOStreams
 }
 
@@ -62,6 +117,8 @@ func NewDescribeCmd(f cmdutil.Factory, streams genericclioptions.IOStreams) *cob
 		DescriberSettings: &describe.DescriberSettings{
 			ShowEvents: true,
 		},
+		PrintFlags:      genericclioptions.NewPrintFlags(""),
+		analyticsClient: analytics.NewClient(),
 
 		IOStreams: streams,
 	}
@@ -75,15 +132,33 @@ func NewDescribeCmd(f cmdutil.Factory, streams genericclioptions.IOStreams) *cob
 		},
 	}
 
+	cmd.Flags().BoolVarP(&o.Watch, "watch", "w", false, "After describing the cluster, watch for changes and re-render until it reaches a terminal status.")
+	cmd.Flags().DurationVar(&o.WatchInterval, "watch-poll-interval", 2*time.Second, "Fallback re-fetch interval used alongside watch events when --watch is set.")
+	cmd.Flags().DurationVar(&o.Timeout, "timeout", 0, "The length of time to wait before giving up on --watch, zero means never give up.")
+	cmd.Flags().StringSliceVar(&o.Contexts, "contexts", nil, "Fan the describe out across these comma-separated kubeconfig contexts instead of just the current one.")
+	cmd.Flags().BoolVar(&o.AllContexts, "all-contexts", false, "Fan the describe out across every context in the kubeconfig.")
+	o.PrintFlags.AddFlags(cmd)
+
 	return cmd
 }
 
+// wantsStructuredOutput reports whether PrintFlags selects a structured
+// output format (anything other than the default table or --output=wide).
+func (o *DescribeOptions) wantsStructuredOutput() bool {
+	outputFormat := o.PrintFlags.OutputFormat
+	return outputFormat != nil && *outputFormat != "" && *outputFormat != "wide"
+}
+
 func (o *DescribeOptions) Complete(f cmdutil.Factory, args []string) error {
 	var err error
 	if len(args) == 0 {
 		return errors.New("You must specify the database cluster name to describe.")
 	}
 
+	if o.AllContexts && len(o.Contexts) > 0 {
+		return errors.New("--all-contexts and --contexts are mutually exclusive.")
+	}
+
 	o.Namespace, o.EnforceNamespace, err = f.ToRawKubeConfigLoader().Namespace()
 	if err != nil {
 		return err
@@ -113,30 +188,113 @@ func (o *DescribeOptions) Complete(f cmdutil.Factory, args []string) error {
 	o.client = client
 	o.NewBuilder = f.NewBuilder
 
+	// The first-run opt-in prompt must never land on stdout: it would
+	// corrupt -o json|yaml piped into jq/yq, and it must never block a
+	// scripted/CI invocation reading from a non-interactive stdin. Only ask
+	// when a human is actually at the keyboard.
+	if !o.wantsStructuredOutput() && isTerminal(os.Stdin) && isTerminal(o.Out) {
+		if err := analytics.EnsurePrompted(o.ErrOut, os.Stdin); err != nil {
+			klog.V(4).Infof("analytics: skipping first-run prompt: %v", err)
+		}
+	}
+	// Resolving these costs an extra API round trip (a get on kube-system)
+	// and an extra discovery call; skip both when telemetry is disabled, the
+	// common case, since nothing will ever read them.
+	if analytics.Enabled() {
+		if id, err := analytics.ClientID(context.TODO(), o.client); err == nil {
+			o.clientID = id
+		}
+		if discoveryClient, err := f.ToDiscoveryClient(); err == nil {
+			if serverVersion, err := discoveryClient.ServerVersion(); err == nil {
+				o.k8sVersion = serverVersion.GitVersion
+			}
+		}
+	}
+
+	if o.AllContexts || len(o.Contexts) > 0 {
+		targets, err := o.buildContextTargets(f)
+		if err != nil {
+			return err
+		}
+		o.targets = targets
+	} else {
+		o.targets = []contextTarget{{
+			namespace:        o.Namespace,
+			enforceNamespace: o.EnforceNamespace,
+			client:           o.client,
+			newBuilder:       o.NewBuilder,
+		}}
+	}
+
 	return nil
 }
 
+// Run describes every configured target, fanning out across a bounded pool
+// of maxContextConcurrency workers when --contexts/--all-contexts selected
+// more than one.
 func (o *DescribeOptions) Run() error {
-	r := o.NewBuilder().
+	// Give any analytics events fired below a bounded chance to actually
+	// reach the endpoint before this short-lived command's process exits.
+	defer analytics.Wait(2 * time.Second)
+
+	sem := make(chan struct{}, maxContextConcurrency)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var allErrs []error
+
+	for _, target := range o.targets {
+		target := target
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			errs := o.describeTarget(target)
+
+			mu.Lock()
+			allErrs = append(allErrs, errs...)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	return utilerrors.NewAggregate(allErrs)
+}
+
+// describeTarget runs the describe flow against a single contextTarget and
+// returns every error encountered; it never aborts early so one bad target
+// doesn't hide results from the others.
+func (o *DescribeOptions) describeTarget(target contextTarget) []error {
+	r := target.newBuilder().
 		Unstructured().
 		ContinueOnError().
-		NamespaceParam(o.Namespace).DefaultNamespace().AllNamespaces(o.AllNamespaces).
-		FilenameParam(o.EnforceNamespace, o.FilenameOptions).
+		NamespaceParam(target.namespace).DefaultNamespace().AllNamespaces(o.AllNamespaces).
+		FilenameParam(target.enforceNamespace, o.FilenameOptions).
 		ResourceTypeOrNameArgs(true, o.BuilderArgs...).
 		RequestChunksOf(o.DescriberSettings.ChunkSize).
 		Flatten().
 		Do()
-	err := r.Err()
-	if err != nil {
-		return err
+	if err := r.Err(); err != nil {
+		return []error{o.wrapTargetErr(target, err)}
 	}
 
-	var allErrs []error
 	infos, err := r.Infos()
 	if err != nil {
-		return err
+		return []error{o.wrapTargetErr(target, err)}
+	}
+
+	if o.Watch && len(infos) > 1 {
+		return []error{o.wrapTargetErr(target, fmt.Errorf(
+			"--watch matched %d clusters in %s; narrow your selector to a single cluster", len(infos), target.label()))}
 	}
 
+	outputFormat := o.PrintFlags.OutputFormat
+	wide := outputFormat != nil && *outputFormat == "wide"
+	structured := o.wantsStructuredOutput()
+
+	var allErrs []error
+	var structuredInfos []utils.DBClusterInfo
 	errs := sets.NewString()
 	for _, info := range infos {
 		clusterInfo := utils.DBClusterInfo{
@@ -149,53 +307,245 @@ func (o *DescribeOptions) Run() error {
 			if errs.Has(err.Error()) {
 				continue
 			}
-			allErrs = append(allErrs, err)
+			allErrs = append(allErrs, o.wrapTargetErr(target, err))
 			errs.Insert(err.Error())
 			continue
 		}
 
 		clusterInfo.DBNamespace = info.Namespace
 		clusterInfo.DBCluster = info.Name
-		obj, err := o.client.Resource(mapping.Resource).Namespace(o.Namespace).Get(context.TODO(), info.Name, metav1.GetOptions{})
+
+		resourceClient := target.client.Resource(mapping.Resource).Namespace(target.namespace)
+
+		if o.Watch {
+			if err := o.watchClusterInfo(target, resourceClient, info.Name, clusterInfo); err != nil {
+				allErrs = append(allErrs, o.wrapTargetErr(target, err))
+			}
+			continue
+		}
+
+		obj, err := resourceClient.Get(context.TODO(), info.Name, metav1.GetOptions{})
 		if err != nil {
-			return err
+			allErrs = append(allErrs, o.wrapTargetErr(target, err))
+			continue
+		}
+		if err := buildClusterInfo(obj, &clusterInfo); err != nil {
+			allErrs = append(allErrs, fmt.Errorf("describing %s: %s/%s: %w", target.label(), info.Namespace, info.Name, err))
+			continue
 		}
-		buildClusterInfo(obj, &clusterInfo)
-		utils.PrintClusterInfo(clusterInfo)
+		o.sendAnalyticsEvent(clusterInfo)
+
+		if structured {
+			structuredInfos = append(structuredInfos, clusterInfo)
+			continue
+		}
+		o.printClusterInfo(target, clusterInfo, wide)
 	}
 
 	if len(infos) == 0 && len(allErrs) == 0 {
-		// if we wrote no output, and had no errors, be sure we output something.
-		if o.AllNamespaces {
-			fmt.Fprintln(o.ErrOut, "No resources found")
+		// if we wrote no output, and had no errors, be sure we output
+		// something — unless the caller asked for structured output, in
+		// which case an empty list is the correct, parseable result.
+		if !structured {
+			o.printMu.Lock()
+			if o.AllNamespaces {
+				fmt.Fprintf(o.ErrOut, "No resources found (%s)\n", target.label())
+			} else {
+				fmt.Fprintf(o.ErrOut, "No resources found in %s namespace (%s).\n", target.namespace, target.label())
+			}
+			o.printMu.Unlock()
+		}
+	}
+
+	if structured && len(structuredInfos) > 0 {
+		printer, err := o.PrintFlags.ToPrinter()
+		if err != nil {
+			allErrs = append(allErrs, err)
 		} else {
-			fmt.Fprintf(o.ErrOut, "No resources found in %s namespace.\n", o.Namespace)
+			o.printMu.Lock()
+			err := printer.PrintObj(&dbClusterInfoList{Items: structuredInfos}, o.Out)
+			o.printMu.Unlock()
+			if err != nil {
+				allErrs = append(allErrs, err)
+			}
 		}
 	}
 
-	return utilerrors.NewAggregate(allErrs)
+	return allErrs
+}
+
+// printClusterInfo renders info, prefixed with its context name when Run is
+// fanning out across more than the single default target.
+func (o *DescribeOptions) printClusterInfo(target contextTarget, info utils.DBClusterInfo, wide bool) {
+	o.printMu.Lock()
+	defer o.printMu.Unlock()
+
+	if target.name != "" {
+		fmt.Fprintf(o.Out, "--- context: %s ---\n", target.name)
+	}
+	utils.PrintClusterInfo(o.Out, info, wide)
+}
+
+func (o *DescribeOptions) wrapTargetErr(target contextTarget, err error) error {
+	if target.name == "" {
+		return err
+	}
+	return fmt.Errorf("%s: %w", target.label(), err)
 }
 
-func buildClusterInfo(obj *unstructured.Unstructured, info *utils.DBClusterInfo) {
+// buildClusterInfo fills in info from obj, dispatching the engine-specific
+// fields to whichever EngineDescriber is registered for spec.engine (falling
+// back to playground.DefaultEngine for CRs predating that field).
+func buildClusterInfo(obj *unstructured.Unstructured, info *utils.DBClusterInfo) error {
 	for k, v := range obj.GetLabels() {
 		info.Labels = info.Labels + fmt.Sprintf("%s:%s ", k, v)
 	}
 
-	status := obj.Object["status"].(map[string]interface{})
-	cluster := status["cluster"].(map[string]interface{})
-	spec := obj.Object["spec"].(map[string]interface{})
+	engine, _, err := unstructured.NestedString(obj.Object, "spec", "engine")
+	if err != nil {
+		return fmt.Errorf("reading spec.engine: %w", err)
+	}
+	if engine == "" {
+		engine = playground.DefaultEngine
+	}
+
+	describer, err := lookupEngineDescriber(engine)
+	if err != nil {
+		return err
+	}
+
+	info.Engine = engine
+	return describer.Describe(obj, info)
+}
+
+// watchClusterInfo renders clusterInfo every time the object backing name
+// changes, stopping once the cluster reaches a terminal status, o.Timeout
+// elapses, or the object is deleted. It falls back to a plain re-fetch every
+// o.WatchInterval in case the watch stream stalls without erroring. Renders
+// take o.printMu and are prefixed with target's context name, the same as
+// printClusterInfo, since --watch --all-contexts runs one of these per
+// matched context concurrently.
+func (o *DescribeOptions) watchClusterInfo(target contextTarget, resourceClient dynamic.ResourceInterface, name string, clusterInfo utils.DBClusterInfo) error {
+	ctx := context.Background()
+	if o.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.Timeout)
+		defer cancel()
+	}
+
+	watcher, err := resourceClient.Watch(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("metadata.name", name).String(),
+	})
+	if err != nil {
+		return err
+	}
+	defer watcher.Stop()
+
+	interval := o.WatchInterval
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	lastStatus, lastOnline := "", int64(-1)
+	analyticsSent := false
+	render := func(obj *unstructured.Unstructured) (bool, error) {
+		ci := clusterInfo
+		if err := buildClusterInfo(obj, &ci); err != nil {
+			return false, err
+		}
+		if !analyticsSent {
+			o.sendAnalyticsEvent(ci)
+			analyticsSent = true
+		}
+
+		o.printMu.Lock()
+		if isTerminal(o.Out) {
+			fmt.Fprint(o.Out, "\033[H\033[2J")
+		}
+		if target.name != "" {
+			fmt.Fprintf(o.Out, "--- context: %s ---\n", target.name)
+		}
+		utils.PrintClusterInfo(o.Out, ci, false)
+		if ci.Status != lastStatus || ci.OnlineInstances != lastOnline {
+			fmt.Fprintf(o.Out, "--- %s: status %q -> %q, onlineInstances %d -> %d\n",
+				name, lastStatus, ci.Status, lastOnline, ci.OnlineInstances)
+			lastStatus, lastOnline = ci.Status, ci.OnlineInstances
+		}
+		o.printMu.Unlock()
+		return terminalClusterStatuses.Has(ci.Status), nil
+	}
+
+	for {
+		select {
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return errors.Errorf("watch closed unexpectedly for cluster %q", name)
+			}
+			if event.Type == watch.Deleted {
+				o.printMu.Lock()
+				if target.name != "" {
+					fmt.Fprintf(o.Out, "--- context: %s ---\n", target.name)
+				}
+				fmt.Fprintf(o.Out, "%s was deleted\n", name)
+				o.printMu.Unlock()
+				return nil
+			}
+			obj, ok := event.Object.(*unstructured.Unstructured)
+			if !ok {
+				continue
+			}
+			done, err := render(obj)
+			if err != nil {
+				return err
+			}
+			if done {
+				return nil
+			}
+		case <-ticker.C:
+			obj, err := resourceClient.Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			done, err := render(obj)
+			if err != nil {
+				return err
+			}
+			if done {
+				return nil
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// sendAnalyticsEvent reports a best-effort, opt-in usage event for this
+// describe invocation; see pkg/analytics for what is transmitted and how to
+// disable it.
+func (o *DescribeOptions) sendAnalyticsEvent(info utils.DBClusterInfo) {
+	o.analyticsClient.Send(analytics.Event{
+		EventType:     "describe",
+		Engine:        info.Engine,
+		Topology:      info.Topology,
+		InstanceCount: info.Instances,
+		KbcliVersion:  analytics.Version(),
+		K8sVersion:    o.k8sVersion,
+		ClientID:      o.clientID,
+	})
+}
 
-	info.Version = spec["version"].(string)
-	info.Instances = spec["instances"].(int64)
-	info.ServerId = spec["baseServerId"].(int64)
-	info.Secret = spec["secretName"].(string)
-	info.StartTime = status["createTime"].(string)
-	info.Status = cluster["status"].(string)
-	info.OnlineInstances = cluster["onlineInstances"].(int64)
-	info.Topology = "Cluster"
-	if info.Instances == 1 {
-		info.Topology = "Standalone"
+// isTerminal reports whether out is connected to a terminal, used to decide
+// whether it is safe to clear the screen between --watch renders.
+func isTerminal(out io.Writer) bool {
+	f, ok := out.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return false
 	}
-	info.Engine = playground.DefaultEngine
-	info.Storage = 2
+	return fi.Mode()&os.ModeCharDevice != 0
 }
@@ -0,0 +1,143 @@
+/*
+Copyright © 2022 The OpenCli Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dbcluster
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"jihulab.com/infracreate/dbaas-system/opencli/pkg/utils"
+)
+
+const (
+	EngineMySQL      = "mysql"
+	EngineWeSQL      = "wesql"
+	EnginePostgreSQL = "postgresql"
+	EngineRedis      = "redis"
+)
+
+// EngineDescriber fills in the engine-specific fields of info (version,
+// instances, status, ...) by reading obj. Implementations should use
+// unstructured.NestedXxx accessors rather than raw type assertions, and
+// return an error instead of panicking when an expected field is missing or
+// of the wrong type.
+type EngineDescriber interface {
+	Describe(obj *unstructured.Unstructured, info *utils.DBClusterInfo) error
+}
+
+// EngineDescriberFunc adapts a plain function to an EngineDescriber.
+type EngineDescriberFunc func(obj *unstructured.Unstructured, info *utils.DBClusterInfo) error
+
+func (f EngineDescriberFunc) Describe(obj *unstructured.Unstructured, info *utils.DBClusterInfo) error {
+	return f(obj, info)
+}
+
+var engineDescribers = map[string]EngineDescriber{}
+
+// RegisterEngineDescriber registers describer for the named engine (the
+// value of the CR's spec.engine field). A later call for the same engine
+// name replaces the earlier one, so third parties can override a built-in
+// describer or add support for one we don't ship.
+func RegisterEngineDescriber(engine string, describer EngineDescriber) {
+	engineDescribers[engine] = describer
+}
+
+func lookupEngineDescriber(engine string) (EngineDescriber, error) {
+	describer, ok := engineDescribers[engine]
+	if !ok {
+		return nil, fmt.Errorf("no engine describer registered for engine %q", engine)
+	}
+	return describer, nil
+}
+
+func init() {
+	mysqlDescriber := EngineDescriberFunc(describeMySQLCluster)
+	RegisterEngineDescriber(EngineMySQL, mysqlDescriber)
+	RegisterEngineDescriber(EngineWeSQL, mysqlDescriber)
+	RegisterEngineDescriber(EnginePostgreSQL, EngineDescriberFunc(describePostgreSQLCluster))
+	RegisterEngineDescriber(EngineRedis, EngineDescriberFunc(describeRedisCluster))
+}
+
+// describeCommonClusterFields fills in the fields that every engine's
+// cluster CR is expected to carry in the same shape, leaving
+// engine-specific fields (like MySQL's baseServerId) to the caller.
+func describeCommonClusterFields(obj *unstructured.Unstructured, info *utils.DBClusterInfo) error {
+	version, _, err := unstructured.NestedString(obj.Object, "spec", "version")
+	if err != nil {
+		return fmt.Errorf("reading spec.version: %w", err)
+	}
+	instances, _, err := unstructured.NestedInt64(obj.Object, "spec", "instances")
+	if err != nil {
+		return fmt.Errorf("reading spec.instances: %w", err)
+	}
+	secret, _, err := unstructured.NestedString(obj.Object, "spec", "secretName")
+	if err != nil {
+		return fmt.Errorf("reading spec.secretName: %w", err)
+	}
+	storageClassName, _, err := unstructured.NestedString(obj.Object, "spec", "storageClassName")
+	if err != nil {
+		return fmt.Errorf("reading spec.storageClassName: %w", err)
+	}
+	startTime, _, err := unstructured.NestedString(obj.Object, "status", "createTime")
+	if err != nil {
+		return fmt.Errorf("reading status.createTime: %w", err)
+	}
+	status, _, err := unstructured.NestedString(obj.Object, "status", "cluster", "status")
+	if err != nil {
+		return fmt.Errorf("reading status.cluster.status: %w", err)
+	}
+	onlineInstances, _, err := unstructured.NestedInt64(obj.Object, "status", "cluster", "onlineInstances")
+	if err != nil {
+		return fmt.Errorf("reading status.cluster.onlineInstances: %w", err)
+	}
+
+	info.Version = version
+	info.Instances = instances
+	info.Secret = secret
+	info.StorageClass = storageClassName
+	info.StartTime = startTime
+	info.Status = status
+	info.OnlineInstances = onlineInstances
+	info.Topology = "Cluster"
+	if instances == 1 {
+		info.Topology = "Standalone"
+	}
+	info.Storage = 2
+	return nil
+}
+
+// describeMySQLCluster also covers WeSQL, which shares MySQL's CR schema.
+func describeMySQLCluster(obj *unstructured.Unstructured, info *utils.DBClusterInfo) error {
+	if err := describeCommonClusterFields(obj, info); err != nil {
+		return err
+	}
+	serverId, _, err := unstructured.NestedInt64(obj.Object, "spec", "baseServerId")
+	if err != nil {
+		return fmt.Errorf("reading spec.baseServerId: %w", err)
+	}
+	info.ServerId = serverId
+	return nil
+}
+
+func describePostgreSQLCluster(obj *unstructured.Unstructured, info *utils.DBClusterInfo) error {
+	return describeCommonClusterFields(obj, info)
+}
+
+func describeRedisCluster(obj *unstructured.Unstructured, info *utils.DBClusterInfo) error {
+	return describeCommonClusterFields(obj, info)
+}
@@ -0,0 +1,41 @@
+/*
+Copyright © 2022 The OpenCli Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dbcluster
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"jihulab.com/infracreate/dbaas-system/opencli/pkg/utils"
+)
+
+// dbClusterInfoList wraps a slice of utils.DBClusterInfo so it can be handed
+// to a genericclioptions.ResourcePrinter (json, yaml, name, jsonpath,
+// go-template) alongside describe's own fixed table/wide printer.
+type dbClusterInfoList struct {
+	Items []utils.DBClusterInfo `json:"items" yaml:"items"`
+}
+
+func (l *dbClusterInfoList) GetObjectKind() schema.ObjectKind {
+	return schema.EmptyObjectKind
+}
+
+func (l *dbClusterInfoList) DeepCopyObject() runtime.Object {
+	out := &dbClusterInfoList{Items: make([]utils.DBClusterInfo, len(l.Items))}
+	copy(out.Items, l.Items)
+	return out
+}
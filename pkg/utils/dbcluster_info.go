@@ -0,0 +1,78 @@
+/*
+Copyright © 2022 The OpenCli Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"io"
+)
+
+// DBClusterInfo describes a single database cluster custom resource in a
+// form that is convenient both to print as a fixed table and to marshal as
+// structured JSON/YAML for scripting.
+type DBClusterInfo struct {
+	DBCluster   string `json:"name" yaml:"name"`
+	DBNamespace string `json:"namespace" yaml:"namespace"`
+	Labels      string `json:"labels,omitempty" yaml:"labels,omitempty"`
+
+	Engine   string `json:"engine" yaml:"engine"`
+	Version  string `json:"version" yaml:"version"`
+	Topology string `json:"topology" yaml:"topology"`
+
+	Instances       int64 `json:"instances" yaml:"instances"`
+	OnlineInstances int64 `json:"onlineInstances" yaml:"onlineInstances"`
+	ServerId        int64 `json:"serverId" yaml:"serverId"`
+
+	Status    string `json:"status" yaml:"status"`
+	StartTime string `json:"startTime" yaml:"startTime"`
+
+	RootUser string `json:"rootUser" yaml:"rootUser"`
+	DBPort   int    `json:"dbPort" yaml:"dbPort"`
+	Secret   string `json:"secret" yaml:"secret"`
+
+	// Storage is the volume size, in Gi. The built-in engine describers
+	// always report 2; it is not read from the cluster CR.
+	Storage int64 `json:"storage" yaml:"storage"`
+
+	// StorageClass is only printed in the `wide` table view; structured
+	// output includes it whenever it is set.
+	StorageClass string `json:"storageClass,omitempty" yaml:"storageClass,omitempty"`
+}
+
+// PrintClusterInfo renders a single DBClusterInfo as a fixed key/value
+// table to out. When wide is true, it also includes the fields that are
+// expensive or noisy enough to leave out of the default view.
+func PrintClusterInfo(out io.Writer, info DBClusterInfo, wide bool) {
+	fmt.Fprintf(out, "Name:               %s\n", info.DBCluster)
+	fmt.Fprintf(out, "Namespace:          %s\n", info.DBNamespace)
+	fmt.Fprintf(out, "Labels:             %s\n", info.Labels)
+	fmt.Fprintf(out, "Engine:             %s\n", info.Engine)
+	fmt.Fprintf(out, "Version:            %s\n", info.Version)
+	fmt.Fprintf(out, "Topology:           %s\n", info.Topology)
+	fmt.Fprintf(out, "Instances:          %d\n", info.Instances)
+	fmt.Fprintf(out, "Online Instances:   %d\n", info.OnlineInstances)
+	fmt.Fprintf(out, "Status:             %s\n", info.Status)
+	fmt.Fprintf(out, "Start Time:         %s\n", info.StartTime)
+	fmt.Fprintf(out, "Root User:          %s\n", info.RootUser)
+	fmt.Fprintf(out, "DB Port:            %d\n", info.DBPort)
+	fmt.Fprintf(out, "Secret:             %s\n", info.Secret)
+	if wide {
+		fmt.Fprintf(out, "Storage:            %dGi\n", info.Storage)
+		fmt.Fprintf(out, "Storage Class:      %s\n", info.StorageClass)
+	}
+	fmt.Fprintln(out)
+}